@@ -0,0 +1,133 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBearerAuthenticator_Sign(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := NewBearerAuthenticator("tok123").Sign(req); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer tok123" {
+		t.Fatalf("Authorization = %q, want %q", got, "Bearer tok123")
+	}
+}
+
+func TestAPIKeyAuthenticator_Sign(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := NewAPIKeyAuthenticator("X-Api-Key", "secret").Sign(req); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if got := req.Header.Get("X-Api-Key"); got != "secret" {
+		t.Fatalf("X-Api-Key = %q, want %q", got, "secret")
+	}
+}
+
+func TestOAuth2ClientCredentialsAuthenticator_SignFetchesAndCachesToken(t *testing.T) {
+	var tokenRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"cached-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	a := NewOAuth2ClientCredentialsAuthenticator(server.URL, "id", "secret", "read")
+
+	req1, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := a.Sign(req1); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if got := req1.Header.Get("Authorization"); got != "Bearer cached-token" {
+		t.Fatalf("Authorization = %q, want %q", got, "Bearer cached-token")
+	}
+
+	req2, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := a.Sign(req2); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected the token to be cached and reused, but the token endpoint was hit %d times", tokenRequests)
+	}
+}
+
+func TestHMACAuthenticator_SignsActualRequestHost(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "http://api.example.com/v1/widgets?b=2&a=1", strings.NewReader(`{"x":1}`))
+
+	a := NewHMACAuthenticator("AKID", "secret")
+	if err := a.Sign(req); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "HMAC-SHA256 Credential=AKID, SignedHeaders=host, Signature=") {
+		t.Fatalf("unexpected Authorization header: %q", auth)
+	}
+
+	// The canonical request must bind to req.Host (the actual target),
+	// not to the always-empty "Host" header, so re-targeting the request
+	// changes the signed canonical form.
+	canonical := a.canonicalRequest(req, []byte(`{"x":1}`))
+	if !strings.Contains(canonical, "host:api.example.com") {
+		t.Fatalf("expected canonical request to bind to the actual host, got %q", canonical)
+	}
+	req.Host = "evil.example.com"
+	retargeted := a.canonicalRequest(req, []byte(`{"x":1}`))
+	if retargeted == canonical {
+		t.Fatalf("expected retargeting the request's host to change the canonical request")
+	}
+}
+
+func TestHMACAuthenticator_CanonicalQueryIsSorted(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/path?z=1&a=2&m=3", nil)
+	a := NewHMACAuthenticator("AKID", "secret")
+	canonical := a.canonicalRequest(req, nil)
+	lines := strings.Split(canonical, "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected at least 3 canonical lines, got %d: %q", len(lines), canonical)
+	}
+	if lines[2] != "a=2&m=3&z=1" {
+		t.Fatalf("canonical query = %q, want sorted %q", lines[2], "a=2&m=3&z=1")
+	}
+}
+
+func TestHMACAuthenticator_StreamingBodyIsNotBuffered(t *testing.T) {
+	pr, pw := io.Pipe()
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/upload", nil)
+	req.Body = &streamingBody{pr}
+	defer pw.Close()
+
+	a := NewHMACAuthenticator("AKID", "secret")
+	if err := a.Sign(req); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	// Sign must not have consumed the pipe: the body must still be the
+	// original *streamingBody rather than a buffered replacement.
+	if _, ok := req.Body.(*streamingBody); !ok {
+		t.Fatalf("expected req.Body to remain a *streamingBody instead of being buffered")
+	}
+}
+
+func TestHMACAuthenticator_CanonicalHeadersIncludeExtras(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set("X-Custom", "value")
+	a := &HMACAuthenticator{AccessKey: "AKID", SecretKey: "secret", Headers: []string{"X-Custom"}}
+	names := a.signedHeaders()
+	if len(names) != 2 {
+		t.Fatalf("expected 2 signed headers, got %v", names)
+	}
+	headers := a.canonicalHeaders(req)
+	if !strings.Contains(headers, "x-custom:value") {
+		t.Fatalf("expected canonical headers to include x-custom, got %q", headers)
+	}
+}
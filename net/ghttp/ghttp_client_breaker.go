@@ -0,0 +1,197 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen is returned by DoRequest when the circuit breaker for the
+// request's target host is Open; the request is short-circuited without
+// ever hitting the wire.
+type ErrCircuitOpen struct {
+	Host string
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf(`ghttp: circuit open for host "%s"`, e.Host)
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker trips per host once a rolling window of requests
+// accumulates enough failures, rejecting further requests to that host
+// until a cooldown elapses, at which point a single HalfOpen probe decides
+// whether to close it again.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	hosts            map[string]*hostCircuit
+	failureThreshold int
+	rollingWindow    time.Duration
+	cooldown         time.Duration
+}
+
+// NewCircuitBreaker creates and returns a CircuitBreaker that trips a host
+// after failureThreshold failures within rollingWindow, staying Open for
+// cooldown before allowing a HalfOpen probe.
+func NewCircuitBreaker(failureThreshold int, rollingWindow, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		hosts:            make(map[string]*hostCircuit),
+		failureThreshold: failureThreshold,
+		rollingWindow:    rollingWindow,
+		cooldown:         cooldown,
+	}
+}
+
+// SetCircuitBreaker registers the CircuitBreaker used by the Client.
+func (c *Client) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+func (cb *CircuitBreaker) circuitFor(host string) *hostCircuit {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	hc, ok := cb.hosts[host]
+	if !ok {
+		hc = &hostCircuit{}
+		cb.hosts[host] = hc
+	}
+	return hc
+}
+
+// hostCircuit tracks the breaker state for a single host.
+type hostCircuit struct {
+	mu       sync.Mutex
+	state    circuitState
+	failures []time.Time
+	openedAt time.Time
+}
+
+// allow reports whether a request to this host may proceed, transitioning
+// Open to HalfOpen once the cooldown has elapsed. Only the single request
+// that performs that transition is let through as the HalfOpen probe; every
+// other caller is rejected until recordResult closes or re-opens the
+// circuit, so a recovering backend never sees a fan-out of requests the
+// instant its cooldown expires.
+func (hc *hostCircuit) allow(cb *CircuitBreaker) bool {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	switch hc.state {
+	case circuitClosed:
+		return true
+	case circuitHalfOpen:
+		return false
+	default: // circuitOpen
+		if time.Since(hc.openedAt) < cb.cooldown {
+			return false
+		}
+		hc.state = circuitHalfOpen
+		return true
+	}
+}
+
+// recordResult updates the circuit state after a request completes. A
+// successful HalfOpen probe closes the circuit; a failed one re-opens it.
+func (hc *hostCircuit) recordResult(cb *CircuitBreaker, success bool) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if success {
+		hc.state = circuitClosed
+		hc.failures = nil
+		return
+	}
+	if hc.state == circuitHalfOpen {
+		hc.trip()
+		return
+	}
+	now := time.Now()
+	hc.failures = append(hc.failures, now)
+	cutoff := now.Add(-cb.rollingWindow)
+	i := 0
+	for ; i < len(hc.failures); i++ {
+		if hc.failures[i].After(cutoff) {
+			break
+		}
+	}
+	hc.failures = hc.failures[i:]
+	if len(hc.failures) >= cb.failureThreshold {
+		hc.trip()
+	}
+}
+
+func (hc *hostCircuit) trip() {
+	hc.state = circuitOpen
+	hc.openedAt = time.Now()
+	hc.failures = nil
+}
+
+// circuitBreakerMiddleware short-circuits requests to a host whose breaker
+// is Open, and otherwise records the outcome of the request against that
+// host's breaker state. Requests/Failures counters are tracked in DoRequest
+// itself, independent of whether a breaker is configured, so this
+// middleware only needs to maintain ShortCircuits.
+func (c *Client) circuitBreakerMiddleware(cli *Client, req *http.Request) (*ClientResponse, error) {
+	hc := c.breaker.circuitFor(req.URL.Host)
+	if !hc.allow(c.breaker) {
+		c.stats.addShortCircuit()
+		return nil, &ErrCircuitOpen{Host: req.URL.Host}
+	}
+	resp, err := cli.MiddlewareNext(req)
+	hc.recordResult(c.breaker, requestSucceeded(resp, err))
+	return resp, err
+}
+
+// requestSucceeded reports whether a request's outcome counts as a success
+// for circuit-breaker and stats purposes: no transport error, and a
+// response status below 500.
+func requestSucceeded(resp *ClientResponse, err error) bool {
+	return err == nil && resp != nil && resp.Response != nil && resp.Response.StatusCode < http.StatusInternalServerError
+}
+
+// ClientStats is a point-in-time snapshot of a Client's request counters,
+// suitable for wiring into Prometheus or similar monitoring.
+type ClientStats struct {
+	Requests      int64
+	Failures      int64
+	ShortCircuits int64
+	RateLimited   int64
+}
+
+// clientStats holds the atomic counters backing Client.Stats().
+type clientStats struct {
+	requests      int64
+	failures      int64
+	shortCircuits int64
+	rateLimited   int64
+}
+
+func (s *clientStats) addRequest()      { atomic.AddInt64(&s.requests, 1) }
+func (s *clientStats) addFailure()      { atomic.AddInt64(&s.failures, 1) }
+func (s *clientStats) addShortCircuit() { atomic.AddInt64(&s.shortCircuits, 1) }
+func (s *clientStats) addRateLimited()  { atomic.AddInt64(&s.rateLimited, 1) }
+
+// Stats returns a snapshot of the request counters: Requests and Failures
+// are counted by DoRequest, ShortCircuits by the circuit breaker middleware
+// and RateLimited by the rate limiter middleware.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Requests:      atomic.LoadInt64(&c.stats.requests),
+		Failures:      atomic.LoadInt64(&c.stats.failures),
+		ShortCircuits: atomic.LoadInt64(&c.stats.shortCircuits),
+		RateLimited:   atomic.LoadInt64(&c.stats.rateLimited),
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHostCircuit_TripsAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker(3, time.Minute, time.Minute)
+	hc := cb.circuitFor("example.com")
+
+	for i := 0; i < 2; i++ {
+		if !hc.allow(cb) {
+			t.Fatalf("expected circuit to stay closed before threshold")
+		}
+		hc.recordResult(cb, false)
+	}
+	if !hc.allow(cb) {
+		t.Fatalf("expected circuit to still allow the 3rd attempt")
+	}
+	hc.recordResult(cb, false)
+
+	if hc.allow(cb) {
+		t.Fatalf("expected circuit to be open after reaching the failure threshold")
+	}
+}
+
+func TestHostCircuit_HalfOpenGatesASingleProbe(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, 0) // cooldown 0: open -> half-open immediately.
+	hc := cb.circuitFor("example.com")
+
+	if !hc.allow(cb) {
+		t.Fatalf("expected circuit to start closed")
+	}
+	hc.recordResult(cb, false) // Trips the circuit (threshold is 1).
+
+	// The first caller after the (zero) cooldown becomes the HalfOpen probe.
+	if !hc.allow(cb) {
+		t.Fatalf("expected the first caller after cooldown to be let through as the probe")
+	}
+	// Every other concurrent caller must be rejected while the probe is in
+	// flight, instead of being let through alongside it.
+	for i := 0; i < 5; i++ {
+		if hc.allow(cb) {
+			t.Fatalf("expected concurrent callers to be rejected while a HalfOpen probe is in flight")
+		}
+	}
+
+	// A successful probe closes the circuit again.
+	hc.recordResult(cb, true)
+	if !hc.allow(cb) {
+		t.Fatalf("expected circuit to be closed after a successful probe")
+	}
+}
+
+func TestHostCircuit_FailedProbeReopens(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, time.Minute)
+	hc := cb.circuitFor("example.com")
+
+	hc.allow(cb)
+	hc.recordResult(cb, false) // Open.
+	// Backdate the trip so the cooldown has elapsed, rather than sleeping.
+	hc.mu.Lock()
+	hc.openedAt = time.Now().Add(-2 * time.Minute)
+	hc.mu.Unlock()
+	hc.allow(cb)               // Transition to HalfOpen, consumes the probe slot.
+	hc.recordResult(cb, false) // Probe failed: re-open.
+
+	if hc.allow(cb) {
+		t.Fatalf("expected the circuit to re-open immediately after a failed probe")
+	}
+}
+
+func TestRequestSucceeded(t *testing.T) {
+	cases := []struct {
+		name string
+		resp *ClientResponse
+		err  error
+		want bool
+	}{
+		{"transport error", nil, errTransport, false},
+		{"nil response", nil, nil, false},
+		{"2xx", &ClientResponse{Response: &http.Response{StatusCode: 200}}, nil, true},
+		{"4xx counts as success", &ClientResponse{Response: &http.Response{StatusCode: 404}}, nil, true},
+		{"5xx", &ClientResponse{Response: &http.Response{StatusCode: 503}}, nil, false},
+	}
+	for _, c := range cases {
+		if got := requestSucceeded(c.resp, c.err); got != c.want {
+			t.Errorf("%s: requestSucceeded() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/internal/intlog"
+	"github.com/gogf/gf/internal/json"
+	"github.com/gogf/gf/internal/utils"
+)
+
+// CassetteMode selects how a Client's cassette behaves.
+type CassetteMode int
+
+const (
+	// CassetteRecord always hits the real transport and records every
+	// round-trip. The existing cassette file is not loaded; it's rewritten
+	// with the interactions recorded since SetCassette, replacing any
+	// earlier content.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay never hits the real transport; DoRequest fails if no
+	// recorded interaction matches the request.
+	CassetteReplay
+	// CassetteReplayOrRecord replays a matching interaction if one exists,
+	// otherwise falls through to the real transport and records it.
+	CassetteReplayOrRecord
+)
+
+// CassetteMatcher decides whether a recorded interaction matches an
+// outgoing request, identified by its method, URL and body hash.
+type CassetteMatcher func(recorded *cassetteRequest, method, url, bodyHash string) bool
+
+// CassetteRedactor scrubs sensitive values from an interaction before it's
+// persisted to the cassette file.
+type CassetteRedactor func(interaction *cassetteInteraction)
+
+// SetCassette enables record/replay mode for the client, so that tests
+// calling DoRequest don't have to hit real servers. In CassetteReplay and
+// CassetteReplayOrRecord mode, the cassette file at path is loaded
+// immediately.
+func (c *Client) SetCassette(path string, mode CassetteMode) {
+	cas := &cassette{
+		path: path,
+		mode: mode,
+		headerDenylist: map[string]bool{
+			http.CanonicalHeaderKey("Authorization"): true,
+			http.CanonicalHeaderKey("Cookie"):        true,
+			http.CanonicalHeaderKey("Set-Cookie"):    true,
+		},
+	}
+	if mode != CassetteRecord {
+		if err := cas.load(); err != nil {
+			intlog.Errorf(`%+v`, err)
+		}
+	}
+	c.cassette = cas
+}
+
+// SetCassetteMatcher overrides the matcher used to pair an outgoing
+// request with a recorded interaction. It has no effect before
+// SetCassette has been called.
+func (c *Client) SetCassetteMatcher(m CassetteMatcher) {
+	if c.cassette != nil {
+		c.cassette.matcher = m
+	}
+}
+
+// SetCassetteRedactor registers a hook that scrubs sensitive values from
+// an interaction right before it's written to the cassette file.
+func (c *Client) SetCassetteRedactor(r CassetteRedactor) {
+	if c.cassette != nil {
+		c.cassette.redactor = r
+	}
+}
+
+// SetCassetteHeaderDenylist overrides the set of header names excluded
+// from a recorded interaction, replacing the default of "Authorization",
+// "Cookie" and "Set-Cookie".
+func (c *Client) SetCassetteHeaderDenylist(headers ...string) {
+	if c.cassette == nil {
+		return
+	}
+	deny := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		deny[http.CanonicalHeaderKey(h)] = true
+	}
+	c.cassette.headerDenylist = deny
+}
+
+// cassetteRequest is the persisted, redacted form of a request.
+type cassetteRequest struct {
+	Method   string            `json:"method"`
+	Url      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	BodyHash string            `json:"bodyHash"`
+}
+
+// cassetteResponse is the persisted, redacted form of a response.
+type cassetteResponse struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+// cassetteInteraction is a single recorded round-trip.
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+// cassette stores and replays the interactions recorded for a Client.
+type cassette struct {
+	mu             sync.Mutex
+	path           string
+	mode           CassetteMode
+	headerDenylist map[string]bool
+	matcher        CassetteMatcher
+	redactor       CassetteRedactor
+	interactions   []cassetteInteraction
+}
+
+func (cas *cassette) load() error {
+	data, err := ioutil.ReadFile(cas.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, &cas.interactions)
+}
+
+func (cas *cassette) save() error {
+	cas.mu.Lock()
+	data, err := json.Marshal(cas.interactions)
+	cas.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cas.path, data, 0644)
+}
+
+// replay looks up a recorded interaction matching req, consuming and
+// restoring its body in the process so the caller can still send it. cli is
+// attached to the returned ClientResponse so that ClientResponse.Decode can
+// still resolve a decoder, exactly as it would for a response that actually
+// went over the wire.
+//
+// A *streamingBody, as produced for file uploads when SetBodyStreaming is
+// enabled, is left untouched rather than read into memory: buffering it here
+// would defeat the point of streaming, so such requests are matched on
+// method and URL alone. Cassette mode and SetBodyStreaming can therefore be
+// combined safely, but don't rely on the body content to disambiguate
+// streamed uploads in a cassette.
+func (cas *cassette) replay(cli *Client, req *http.Request) (resp *ClientResponse, matched bool, err error) {
+	var bodyContent []byte
+	if req.Body != nil {
+		if _, streaming := req.Body.(*streamingBody); !streaming {
+			if bodyContent, err = ioutil.ReadAll(req.Body); err != nil {
+				return nil, false, err
+			}
+			req.Body = utils.NewReadCloser(bodyContent, false)
+		}
+	}
+	hash := cassetteBodyHash(bodyContent)
+	matcher := cas.matcher
+	if matcher == nil {
+		matcher = defaultCassetteMatcher
+	}
+	cas.mu.Lock()
+	defer cas.mu.Unlock()
+	for i := range cas.interactions {
+		recorded := cas.interactions[i]
+		if matcher(&recorded.Request, req.Method, req.URL.String(), hash) {
+			return recorded.toClientResponse(cli, req), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// record appends the outcome of req/resp as a new interaction and persists
+// the cassette, restoring resp's body so the caller can still read it.
+func (cas *cassette) record(req *http.Request, resp *ClientResponse, reqBody []byte) {
+	if resp == nil || resp.Response == nil {
+		return
+	}
+	respBody, err := ioutil.ReadAll(resp.Response.Body)
+	if err != nil {
+		intlog.Errorf(`%+v`, err)
+		return
+	}
+	if cErr := resp.Response.Body.Close(); cErr != nil {
+		intlog.Errorf(`%+v`, cErr)
+	}
+	resp.Response.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := cassetteInteraction{
+		Request: cassetteRequest{
+			Method:   req.Method,
+			Url:      req.URL.String(),
+			Headers:  cas.filteredHeaders(req.Header),
+			BodyHash: cassetteBodyHash(reqBody),
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.Response.StatusCode,
+			Headers:    cas.filteredHeaders(resp.Response.Header),
+			Body:       string(respBody),
+		},
+	}
+	if cas.redactor != nil {
+		cas.redactor(&interaction)
+	}
+	cas.mu.Lock()
+	cas.interactions = append(cas.interactions, interaction)
+	cas.mu.Unlock()
+	if err := cas.save(); err != nil {
+		intlog.Errorf(`%+v`, err)
+	}
+}
+
+func (cas *cassette) filteredHeaders(h http.Header) map[string]string {
+	result := make(map[string]string, len(h))
+	for k, v := range h {
+		if cas.headerDenylist[http.CanonicalHeaderKey(k)] || len(v) == 0 {
+			continue
+		}
+		result[k] = v[0]
+	}
+	return result
+}
+
+// toClientResponse turns a recorded interaction back into a ClientResponse
+// usable as-is by callers of DoRequest, including ClientResponse.Decode,
+// which needs the client field to resolve a decoder.
+func (ia *cassetteInteraction) toClientResponse(cli *Client, req *http.Request) *ClientResponse {
+	header := make(http.Header, len(ia.Response.Headers))
+	for k, v := range ia.Response.Headers {
+		header.Set(k, v)
+	}
+	return &ClientResponse{
+		request: req,
+		client:  cli,
+		Response: &http.Response{
+			StatusCode: ia.Response.StatusCode,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(ia.Response.Body)),
+			Request:    req,
+		},
+	}
+}
+
+func defaultCassetteMatcher(recorded *cassetteRequest, method, url, bodyHash string) bool {
+	return recorded.Method == method && recorded.Url == url && recorded.BodyHash == bodyHash
+}
+
+func cassetteBodyHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
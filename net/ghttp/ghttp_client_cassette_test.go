@@ -0,0 +1,132 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette_SaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	original := &cassette{path: path}
+	original.interactions = []cassetteInteraction{{
+		Request: cassetteRequest{
+			Method:   http.MethodGet,
+			Url:      "http://example.com/widgets",
+			BodyHash: cassetteBodyHash(nil),
+		},
+		Response: cassetteResponse{
+			StatusCode: 200,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       `{"ok":true}`,
+		},
+	}}
+	if err := original.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded := &cassette{path: path}
+	if err := loaded.load(); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded.interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %d", len(loaded.interactions))
+	}
+	if loaded.interactions[0].Response.Body != `{"ok":true}` {
+		t.Fatalf("unexpected loaded body: %s", loaded.interactions[0].Response.Body)
+	}
+}
+
+func TestCassette_LoadMissingFileIsNotAnError(t *testing.T) {
+	cas := &cassette{path: filepath.Join(t.TempDir(), "does-not-exist.json")}
+	if err := cas.load(); err != nil {
+		t.Fatalf("expected a missing cassette file to load as empty, got %v", err)
+	}
+	if len(cas.interactions) != 0 {
+		t.Fatalf("expected no interactions")
+	}
+}
+
+func TestCassette_ReplayMatchesOnMethodURLAndBody(t *testing.T) {
+	cli := &Client{}
+	cas := &cassette{interactions: []cassetteInteraction{{
+		Request: cassetteRequest{
+			Method:   http.MethodPost,
+			Url:      "http://example.com/widgets",
+			BodyHash: cassetteBodyHash([]byte(`{"name":"gizmo"}`)),
+		},
+		Response: cassetteResponse{StatusCode: 201, Body: `{"id":1}`},
+	}}}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/widgets", strings.NewReader(`{"name":"gizmo"}`))
+	resp, matched, err := cas.replay(cli, req)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if !matched {
+		t.Fatalf("expected a matching interaction")
+	}
+	if resp.client != cli {
+		t.Fatalf("expected the replayed response to carry the originating client so Decode can resolve a decoder")
+	}
+	if resp.Response.StatusCode != 201 {
+		t.Fatalf("status = %d, want 201", resp.Response.StatusCode)
+	}
+
+	// A different body must not match.
+	req2, _ := http.NewRequest(http.MethodPost, "http://example.com/widgets", strings.NewReader(`{"name":"other"}`))
+	_, matched2, err := cas.replay(cli, req2)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if matched2 {
+		t.Fatalf("expected no match for a differing request body")
+	}
+}
+
+func TestCassette_ReplayDoesNotBufferStreamingBody(t *testing.T) {
+	cli := &Client{}
+	cas := &cassette{interactions: []cassetteInteraction{{
+		Request: cassetteRequest{
+			Method:   http.MethodPost,
+			Url:      "http://example.com/upload",
+			BodyHash: cassetteBodyHash(nil),
+		},
+		Response: cassetteResponse{StatusCode: 200},
+	}}}
+
+	pr, pw := io.Pipe()
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/upload", nil)
+	req.Body = &streamingBody{pr}
+	defer pw.Close()
+
+	_, matched, err := cas.replay(cli, req)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if !matched {
+		t.Fatalf("expected method+URL match without consuming the streaming body")
+	}
+	// The pipe must still be untouched: the replay must not have read from it.
+	if _, ok := req.Body.(*streamingBody); !ok {
+		t.Fatalf("expected req.Body to remain a *streamingBody instead of being replaced by a buffered reader")
+	}
+}
+
+func TestDefaultCassetteMatcher(t *testing.T) {
+	recorded := &cassetteRequest{Method: http.MethodGet, Url: "http://example.com", BodyHash: "abc"}
+	if !defaultCassetteMatcher(recorded, http.MethodGet, "http://example.com", "abc") {
+		t.Fatalf("expected exact match to succeed")
+	}
+	if defaultCassetteMatcher(recorded, http.MethodGet, "http://example.com", "different") {
+		t.Fatalf("expected a body hash mismatch to fail")
+	}
+}
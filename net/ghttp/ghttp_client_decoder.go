@@ -0,0 +1,168 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gogf/gf/internal/json"
+)
+
+// ClientDecoder decodes a raw response body into v, according to the
+// response's Content-Type. It's registered per media type through
+// Client.RegisterDecoder and dispatched by ClientResponse.Decode.
+type ClientDecoder interface {
+	Decode(data []byte, v interface{}) error
+}
+
+// ClientDecoderFunc is an adapter allowing an ordinary function to be used
+// as a ClientDecoder.
+type ClientDecoderFunc func(data []byte, v interface{}) error
+
+func (f ClientDecoderFunc) Decode(data []byte, v interface{}) error {
+	return f(data, v)
+}
+
+// defaultDecoders are the built-in decoders registered for every Client,
+// keyed by media type. Client.RegisterDecoder overrides or extends them on
+// a per-client basis.
+//
+// Only dependency-free formats are registered here. msgpack and protobuf
+// pull in third-party modules that most consumers of net/ghttp never need,
+// so they're registered from ghttp_client_decoder_extra.go instead, which is
+// built only under the "ghttp_extra_codecs" tag (`go build -tags
+// ghttp_extra_codecs`).
+var defaultDecoders = map[string]ClientDecoder{
+	"application/json": ClientDecoderFunc(func(data []byte, v interface{}) error {
+		return json.Unmarshal(data, v)
+	}),
+	"application/xml": ClientDecoderFunc(xml.Unmarshal),
+	"text/csv":        ClientDecoderFunc(decodeCSV),
+}
+
+// decodeCSV decodes CSV content into *[][]string.
+func decodeCSV(data []byte, v interface{}) error {
+	records, ok := v.(*[][]string)
+	if !ok {
+		return fmt.Errorf("ghttp: CSV decoding target must be *[][]string, got %T", v)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	*records = rows
+	return nil
+}
+
+// RegisterDecoder registers a ClientDecoder for the given media type,
+// overriding the default decoder of the same name if one was already
+// registered. It's consulted by ClientResponse.Decode according to the
+// response's Content-Type.
+func (c *Client) RegisterDecoder(mime string, dec ClientDecoder) {
+	if c.decoders == nil {
+		c.decoders = make(map[string]ClientDecoder)
+	}
+	c.decoders[mime] = dec
+}
+
+// decoderFor returns the ClientDecoder registered for mime, preferring a
+// per-client decoder over the package default.
+func (c *Client) decoderFor(mime string) (ClientDecoder, bool) {
+	if dec, ok := c.decoders[mime]; ok {
+		return dec, true
+	}
+	dec, ok := defaultDecoders[mime]
+	return dec, ok
+}
+
+// Decode reads the response body and decodes it into v according to the
+// response's Content-Type, dispatching to the ClientDecoder registered for
+// that media type. This removes the manual ReadAll + json.Unmarshal pattern
+// for typed responses.
+func (r *ClientResponse) Decode(v interface{}) error {
+	mime := r.Header.Get("Content-Type")
+	if idx := strings.IndexByte(mime, ';'); idx >= 0 {
+		mime = mime[:idx]
+	}
+	mime = strings.TrimSpace(mime)
+	dec, ok := r.client.decoderFor(mime)
+	if !ok {
+		return fmt.Errorf(`ghttp: no ClientDecoder registered for Content-Type "%s"`, mime)
+	}
+	data, err := ioutil.ReadAll(r.Response.Body)
+	if err != nil {
+		return err
+	}
+	return dec.Decode(data, v)
+}
+
+// SetAcceptEncoding sets the "Accept-Encoding" header sent with every
+// request, and works together with the transparent response decompression
+// applied in callRequest for gzip/deflate/br encoded responses.
+func (c *Client) SetAcceptEncoding(encodings ...string) {
+	c.acceptEncoding = strings.Join(encodings, ", ")
+}
+
+// newBrotliReader decodes "br"-encoded response bodies. It's nil unless the
+// package is built with the "ghttp_extra_codecs" tag, which registers it
+// from ghttp_client_decoder_extra.go, keeping the github.com/andybalholm/brotli
+// dependency out of the default build.
+var newBrotliReader func(io.Reader) io.ReadCloser
+
+// decompressBody replaces resp.Body with a transparent decompressing
+// io.ReadCloser when the response declares a supported Content-Encoding,
+// so that callers never have to deal with compression themselves.
+func decompressBody(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "":
+		return nil
+	case "gzip":
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+		resp.Body = &decompressedBody{ReadCloser: reader, source: resp.Body}
+	case "deflate":
+		resp.Body = &decompressedBody{ReadCloser: flate.NewReader(resp.Body), source: resp.Body}
+	case "br":
+		if newBrotliReader == nil {
+			return fmt.Errorf(`ghttp: "br" response decompression requires building with the "ghttp_extra_codecs" tag`)
+		}
+		resp.Body = &decompressedBody{ReadCloser: newBrotliReader(resp.Body), source: resp.Body}
+	default:
+		return nil
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	return nil
+}
+
+// decompressedBody wraps a decompressing reader together with the original
+// compressed body so both get closed together.
+type decompressedBody struct {
+	io.ReadCloser
+	source io.ReadCloser
+}
+
+func (b *decompressedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if sErr := b.source.Close(); sErr != nil && err == nil {
+		err = sErr
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+//go:build ghttp_extra_codecs
+// +build ghttp_extra_codecs
+
+package ghttp
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/andybalholm/brotli"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// init registers the msgpack/protobuf decoders and "br" response
+// decompression. They're only compiled in under the "ghttp_extra_codecs"
+// build tag, so that building net/ghttp normally doesn't pull in these
+// third-party modules for consumers who only ever decode JSON/XML/CSV.
+func init() {
+	defaultDecoders["application/x-msgpack"] = ClientDecoderFunc(func(data []byte, v interface{}) error {
+		return msgpack.Unmarshal(data, v)
+	})
+	defaultDecoders["application/x-protobuf"] = ClientDecoderFunc(func(data []byte, v interface{}) error {
+		m, ok := v.(proto.Message)
+		if !ok {
+			return fmt.Errorf("ghttp: %T does not implement proto.Message", v)
+		}
+		return proto.Unmarshal(data, m)
+	})
+	newBrotliReader = func(r io.Reader) io.ReadCloser {
+		return ioutil.NopCloser(brotli.NewReader(r))
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestClientResponse_DecodeJSON(t *testing.T) {
+	resp := &ClientResponse{
+		client: &Client{},
+		Response: &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			Body:   ioutil.NopCloser(bytes.NewReader([]byte(`{"name":"gopher"}`))),
+		},
+	}
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := resp.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if v.Name != "gopher" {
+		t.Fatalf("Name = %q, want %q", v.Name, "gopher")
+	}
+}
+
+func TestClientResponse_DecodeCSV(t *testing.T) {
+	resp := &ClientResponse{
+		client: &Client{},
+		Response: &http.Response{
+			Header: http.Header{"Content-Type": []string{"text/csv"}},
+			Body:   ioutil.NopCloser(bytes.NewReader([]byte("a,b\n1,2\n"))),
+		},
+	}
+	var records [][]string
+	if err := resp.Decode(&records); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if len(records) != 2 || records[1][0] != "1" || records[1][1] != "2" {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+}
+
+func TestClientResponse_DecodeUnregisteredContentType(t *testing.T) {
+	resp := &ClientResponse{
+		client: &Client{},
+		Response: &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/x-protobuf"}},
+			Body:   ioutil.NopCloser(bytes.NewReader(nil)),
+		},
+	}
+	// Without the "ghttp_extra_codecs" build tag, protobuf has no default
+	// decoder registered; Decode must report that clearly instead of
+	// silently doing nothing.
+	if err := resp.Decode(&struct{}{}); err == nil {
+		t.Fatalf("expected an error for an unregistered Content-Type")
+	}
+}
+
+func TestClient_RegisterDecoderOverridesDefault(t *testing.T) {
+	c := &Client{}
+	var called bool
+	c.RegisterDecoder("application/json", ClientDecoderFunc(func(data []byte, v interface{}) error {
+		called = true
+		return nil
+	}))
+	resp := &ClientResponse{
+		client: c,
+		Response: &http.Response{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   ioutil.NopCloser(bytes.NewReader([]byte(`{}`))),
+		},
+	}
+	if err := resp.Decode(&struct{}{}); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !called {
+		t.Fatalf("expected the per-client decoder to be used instead of the default")
+	}
+}
+
+func TestDecompressBody_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	gw.Write([]byte("hello gzip"))
+	gw.Close()
+
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   ioutil.NopCloser(bytes.NewReader(buf.Bytes())),
+	}
+	if err := decompressBody(resp); err != nil {
+		t.Fatalf("decompressBody failed: %v", err)
+	}
+	got, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed reading decompressed body: %v", err)
+	}
+	if string(got) != "hello gzip" {
+		t.Fatalf("decompressed body = %q, want %q", got, "hello gzip")
+	}
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Fatalf("expected Content-Encoding to be cleared after decompression")
+	}
+}
+
+func TestDecompressBody_BrWithoutExtraCodecsTag(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"br"}},
+		Body:   ioutil.NopCloser(bytes.NewReader(nil)),
+	}
+	// Without the "ghttp_extra_codecs" build tag, newBrotliReader is nil;
+	// decompressBody must report that instead of panicking or silently
+	// passing the still-compressed body through.
+	if err := decompressBody(resp); err == nil {
+		t.Fatalf("expected an error decompressing \"br\" without the extra-codecs tag")
+	}
+}
+
+func TestDecompressBody_NoEncodingIsNoop(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{},
+		Body:   ioutil.NopCloser(bytes.NewReader([]byte("plain"))),
+	}
+	if err := decompressBody(resp); err != nil {
+		t.Fatalf("decompressBody failed: %v", err)
+	}
+	got, _ := ioutil.ReadAll(resp.Body)
+	if string(got) != "plain" {
+		t.Fatalf("body = %q, want %q", got, "plain")
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/gogf/gf/os/gfile"
+	"github.com/gogf/gf/util/gconv"
+)
+
+// ClientFile wraps a single multipart file upload part. It can be passed
+// directly as a value in the `data` map given to DoRequest/Post/etc.,
+// alongside ordinary string params, instead of using the "@file:" string
+// syntax.
+type ClientFile struct {
+	Path        string    // Local file path to be uploaded.
+	Field       string    // Form field name; the data map key is used if Field is empty.
+	FileName    string    // Uploaded file name; the base name of Path is used if FileName is empty.
+	ContentType string    // Part Content-Type; "application/octet-stream" is used if empty.
+	Reader      io.Reader // Content source; Path is opened and used if Reader is nil.
+}
+
+// ClientUploadProgressFunc is the callback registered through
+// Client.SetUploadProgress. It's invoked after every chunk written for a
+// file upload part, with the accumulated bytes written and the total size
+// of that part (total is -1 if the size could not be determined).
+type ClientUploadProgressFunc func(field string, written, total int64)
+
+// SetBodyStreaming enables streaming request body construction for file
+// uploads: the multipart body is written into an io.Pipe on the fly by a
+// background goroutine instead of being buffered entirely in memory, and
+// "Content-Length" is left unset so the request is sent using chunked
+// transfer encoding. It has no effect on requests that don't upload files.
+func (c *Client) SetBodyStreaming(enabled bool) {
+	c.bodyStreaming = enabled
+}
+
+// SetUploadProgress sets the callback notified on every chunk written for a
+// file upload part, in both buffered and streaming mode (SetBodyStreaming
+// only changes how the body is transmitted, not whether progress is
+// reported). It has no effect on requests that don't upload files.
+func (c *Client) SetUploadProgress(f ClientUploadProgressFunc) {
+	c.uploadProgress = f
+}
+
+// fileUploadField represents a single multipart form field, which is
+// either a plain value or a file to be uploaded.
+type fileUploadField struct {
+	Name  string
+	Value string
+	File  *ClientFile
+}
+
+// streamingBody tags a request body as being produced on the fly through
+// an io.Pipe, so that callRequest does not buffer it into memory for
+// dumping or retrying.
+type streamingBody struct {
+	io.ReadCloser
+}
+
+// clientFileUploadFields extracts upload fields from a `data` map that
+// mixes plain values with ClientFile/*ClientFile entries. It returns
+// hasFile = false if v is not a field map or holds no ClientFile, allowing
+// the caller to fall back to the legacy "@file:" string syntax.
+func clientFileUploadFields(v interface{}) (fields []fileUploadField, hasFile bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for k, item := range m {
+		switch f := item.(type) {
+		case ClientFile:
+			fields = append(fields, fileUploadField{Name: fileFieldName(k, f.Field), File: &f})
+			hasFile = true
+		case *ClientFile:
+			fields = append(fields, fileUploadField{Name: fileFieldName(k, f.Field), File: f})
+			hasFile = true
+		default:
+			fields = append(fields, fileUploadField{Name: k, Value: gconv.String(item)})
+		}
+	}
+	return fields, hasFile
+}
+
+func fileFieldName(key, field string) string {
+	if field != "" {
+		return field
+	}
+	return key
+}
+
+// parseFileUploadParam parses the "k=v&k2=@file:path" parameter string
+// built by BuildParams into upload fields. It recognizes the extended
+// "@file:path;name=foo;type=image/png" syntax for an explicit part name
+// and Content-Type.
+func parseFileUploadParam(param string) ([]fileUploadField, error) {
+	fields := make([]fileUploadField, 0)
+	for _, item := range strings.Split(param, "&") {
+		array := strings.SplitN(item, "=", 2)
+		if len(array) != 2 {
+			continue
+		}
+		key, value := array[0], array[1]
+		if len(value) <= 6 || value[0:6] != "@file:" {
+			fields = append(fields, fileUploadField{Name: key, Value: value})
+			continue
+		}
+		segments := strings.Split(value[6:], ";")
+		path := segments[0]
+		if !gfile.Exists(path) {
+			return nil, fmt.Errorf(`"%s" does not exist`, path)
+		}
+		file := &ClientFile{Path: path}
+		for _, seg := range segments[1:] {
+			kv := strings.SplitN(seg, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "name":
+				file.Field = kv[1]
+			case "type":
+				file.ContentType = kv[1]
+			}
+		}
+		fields = append(fields, fileUploadField{Name: fileFieldName(key, file.Field), File: file})
+	}
+	return fields, nil
+}
+
+// buildBufferedMultipartBody writes all fields into an in-memory buffer,
+// the behavior used prior to streaming support and still the default when
+// SetBodyStreaming is not enabled.
+func (c *Client) buildBufferedMultipartBody(fields []fileUploadField) (*bytes.Buffer, string, error) {
+	buffer := new(bytes.Buffer)
+	writer := multipart.NewWriter(buffer)
+	for _, field := range fields {
+		if field.File == nil {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if err := c.writeFilePart(writer, field.Name, field.File); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buffer, writer.FormDataContentType(), nil
+}
+
+// buildStreamingMultipartBody builds the request body for a file-uploading
+// request using an io.Pipe, writing the multipart parts on the fly from a
+// background goroutine so the payload never needs to be buffered in full.
+// It returns the pipe reader to be used as http.Request.Body along with the
+// multipart Content-Type, including its boundary.
+func (c *Client) buildStreamingMultipartBody(fields []fileUploadField) (io.ReadCloser, string, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	contentType := writer.FormDataContentType()
+	go func() {
+		var err error
+		for _, field := range fields {
+			if field.File == nil {
+				err = writer.WriteField(field.Name, field.Value)
+			} else {
+				err = c.writeFilePart(writer, field.Name, field.File)
+			}
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return &streamingBody{pr}, contentType, nil
+}
+
+// writeFilePart writes a single file part into the multipart writer,
+// reporting progress through the client's upload progress callback if one
+// is registered.
+func (c *Client) writeFilePart(writer *multipart.Writer, field string, file *ClientFile) error {
+	reader := file.Reader
+	var total int64 = -1
+	if reader == nil {
+		f, err := os.Open(file.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if stat, err := f.Stat(); err == nil {
+			total = stat.Size()
+		}
+		reader = f
+	}
+	fileName := file.FileName
+	if fileName == "" {
+		fileName = gfile.Basename(file.Path)
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(
+		`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(field), quoteEscaper.Replace(fileName),
+	))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	var dst io.Writer = part
+	if c.uploadProgress != nil {
+		dst = &progressPartWriter{Writer: part, field: field, total: total, onWrite: c.uploadProgress}
+	}
+	_, err = io.Copy(dst, reader)
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// progressPartWriter wraps the destination multipart part writer and
+// reports accumulated bytes written through the upload progress callback.
+type progressPartWriter struct {
+	io.Writer
+	field   string
+	total   int64
+	written int64
+	onWrite ClientUploadProgressFunc
+}
+
+func (w *progressPartWriter) Write(p []byte) (n int, err error) {
+	n, err = w.Writer.Write(p)
+	if n > 0 {
+		w.written += int64(n)
+		w.onWrite(w.field, w.written, w.total)
+	}
+	return
+}
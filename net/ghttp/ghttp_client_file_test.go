@@ -0,0 +1,175 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	return path
+}
+
+func TestParseFileUploadParam_ExtendedFileSyntax(t *testing.T) {
+	path := writeTempFile(t, "file contents")
+
+	fields, err := parseFileUploadParam("name=gopher&avatar=@file:" + path + ";name=avatar;type=image/png")
+	if err != nil {
+		t.Fatalf("parseFileUploadParam failed: %v", err)
+	}
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+	if fields[0].Name != "name" || fields[0].Value != "gopher" {
+		t.Fatalf("unexpected plain field: %+v", fields[0])
+	}
+	if fields[1].File == nil {
+		t.Fatalf("expected the second field to be a file upload")
+	}
+	if fields[1].Name != "avatar" {
+		t.Fatalf("Name = %q, want %q (from the ;name= override)", fields[1].Name, "avatar")
+	}
+	if fields[1].File.Path != path {
+		t.Fatalf("File.Path = %q, want %q", fields[1].File.Path, path)
+	}
+	if fields[1].File.ContentType != "image/png" {
+		t.Fatalf("File.ContentType = %q, want %q", fields[1].File.ContentType, "image/png")
+	}
+}
+
+func TestParseFileUploadParam_MissingFile(t *testing.T) {
+	if _, err := parseFileUploadParam("avatar=@file:/does/not/exist"); err == nil {
+		t.Fatalf("expected an error for a nonexistent file path")
+	}
+}
+
+func TestClientFileUploadFields_MixedMap(t *testing.T) {
+	path := writeTempFile(t, "file contents")
+	data := map[string]interface{}{
+		"name":   "gopher",
+		"avatar": ClientFile{Path: path, Field: "avatar"},
+	}
+	fields, hasFile := clientFileUploadFields(data)
+	if !hasFile {
+		t.Fatalf("expected hasFile = true")
+	}
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+
+	// Not a field map at all: falls back to "@file:" string parsing.
+	if _, ok := clientFileUploadFields("name=value"); ok {
+		t.Fatalf("expected hasFile = false for a non-map value")
+	}
+}
+
+func TestBuildBufferedAndStreamingMultipartBody_Equivalent(t *testing.T) {
+	path := writeTempFile(t, "streamed upload content")
+	fields := []fileUploadField{
+		{Name: "name", Value: "gopher"},
+		{Name: "avatar", File: &ClientFile{Path: path, ContentType: "text/plain"}},
+	}
+
+	c := &Client{}
+
+	bufferedBody, bufferedContentType, err := c.buildBufferedMultipartBody(fields)
+	if err != nil {
+		t.Fatalf("buildBufferedMultipartBody failed: %v", err)
+	}
+	bufferedParts := readMultipartParts(t, bufferedBody.Bytes(), bufferedContentType)
+
+	streamingBodyReader, streamingContentType, err := c.buildStreamingMultipartBody(fields)
+	if err != nil {
+		t.Fatalf("buildStreamingMultipartBody failed: %v", err)
+	}
+	defer streamingBodyReader.Close()
+	streamingContent, err := ioutil.ReadAll(streamingBodyReader)
+	if err != nil {
+		t.Fatalf("failed reading streaming body: %v", err)
+	}
+	streamingParts := readMultipartParts(t, streamingContent, streamingContentType)
+
+	if len(bufferedParts) != len(streamingParts) {
+		t.Fatalf("part count mismatch: buffered=%d streaming=%d", len(bufferedParts), len(streamingParts))
+	}
+	for name, want := range bufferedParts {
+		got, ok := streamingParts[name]
+		if !ok {
+			t.Fatalf("streaming body missing part %q", name)
+		}
+		if got != want {
+			t.Fatalf("part %q content mismatch: buffered=%q streaming=%q", name, want, got)
+		}
+	}
+}
+
+func readMultipartParts(t *testing.T, body []byte, contentType string) map[string]string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("invalid Content-Type %q: %v", contentType, err)
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	parts := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed reading multipart part: %v", err)
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed reading part content: %v", err)
+		}
+		parts[part.FormName()] = string(content)
+	}
+	return parts
+}
+
+func TestProgressPartWriter_ReportsAccumulatedBytes(t *testing.T) {
+	var calls [][2]int64
+	w := &progressPartWriter{
+		Writer: &bytes.Buffer{},
+		field:  "avatar",
+		total:  10,
+		onWrite: func(field string, written, total int64) {
+			if field != "avatar" {
+				t.Fatalf("field = %q, want %q", field, "avatar")
+			}
+			calls = append(calls, [2]int64{written, total})
+		},
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := w.Write([]byte("12345")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 progress callbacks, got %d", len(calls))
+	}
+	if calls[0][0] != 5 || calls[1][0] != 10 {
+		t.Fatalf("unexpected accumulated byte counts: %+v", calls)
+	}
+	if calls[0][1] != 10 || calls[1][1] != 10 {
+		t.Fatalf("unexpected total byte counts: %+v", calls)
+	}
+}
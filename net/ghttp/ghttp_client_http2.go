@@ -0,0 +1,91 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Option configures the *http2.Transport installed by EnableHTTP2.
+type HTTP2Option func(*http2.Transport)
+
+// WithHTTP2MaxHeaderListSize overrides the SETTINGS_MAX_HEADER_LIST_SIZE
+// advertised to the server for every request on this client.
+func WithHTTP2MaxHeaderListSize(size uint32) HTTP2Option {
+	return func(t *http2.Transport) {
+		t.MaxHeaderListSize = size
+	}
+}
+
+// WithHTTP2ReadIdleTimeout sets how often an idle HTTP/2 connection is
+// health-checked with a PING before being considered dead and recycled.
+func WithHTTP2ReadIdleTimeout(d time.Duration) HTTP2Option {
+	return func(t *http2.Transport) {
+		t.ReadIdleTimeout = d
+	}
+}
+
+// EnableHTTP2 upgrades the client's underlying transport to HTTP/2.
+// "https://" targets negotiate HTTP/2 over TLS, while every "http://"
+// request is sent as h2c (cleartext HTTP/2) with prior knowledge: no
+// HTTP/1.1 "Upgrade: h2c" handshake is attempted and there is no fallback
+// to HTTP/1.1, so "http://" targets must accept cleartext HTTP/2 directly.
+// Stream-level cancellation follows the request's
+// context exactly as with the std lib HTTP/1.1 transport. Because the
+// resulting RoundTripper is installed as the client's Transport, every
+// request still goes through DoRequest's middleware chain, so tracing,
+// auth and retries keep applying uniformly.
+func (c *Client) EnableHTTP2(opts ...HTTP2Option) error {
+	transport, ok := c.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport = &http.Transport{}
+	}
+	h2Transport, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		return err
+	}
+	h2cTransport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+	// Applied to both transports: opts must affect h2c ("http://") traffic
+	// exactly as much as it affects the TLS-negotiated ("https://") one,
+	// since h2cRoundTripper dispatches between them per-request.
+	for _, opt := range opts {
+		opt(h2Transport)
+		opt(h2cTransport)
+	}
+	c.Transport = &h2cRoundTripper{
+		tls: transport,
+		h2c: h2cTransport,
+	}
+	return nil
+}
+
+// h2cRoundTripper dispatches plain "http://" requests over h2c (cleartext
+// HTTP/2) and everything else through the regular TLS-aware transport
+// configured for HTTP/2 by EnableHTTP2.
+type h2cRoundTripper struct {
+	tls http.RoundTripper
+	h2c *http2.Transport
+}
+
+func (rt *h2cRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return rt.h2c.RoundTrip(req)
+	}
+	return rt.tls.RoundTrip(req)
+}
@@ -0,0 +1,126 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a per-host token-bucket rate limiter that can be wired
+// into the Client's middleware chain through SetRateLimit/SetHostRateLimit,
+// blocking requests until a token is available for their target host.
+type RateLimiter struct {
+	mu      sync.Mutex
+	byHost  map[string]*tokenBucket
+	general *tokenBucket
+}
+
+// SetRateLimit sets the default token-bucket rate limit applied to every
+// host that has no more specific limit registered through
+// SetHostRateLimit.
+func (c *Client) SetRateLimit(rps float64, burst int) {
+	limiter := c.ensureRateLimiter()
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.general = newTokenBucket(rps, burst)
+}
+
+// SetHostRateLimit sets a token-bucket rate limit specific to the given
+// host, overriding the default set through SetRateLimit for that host.
+func (c *Client) SetHostRateLimit(host string, rps float64, burst int) {
+	limiter := c.ensureRateLimiter()
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.byHost[host] = newTokenBucket(rps, burst)
+}
+
+func (c *Client) ensureRateLimiter() *RateLimiter {
+	if c.rateLimiter == nil {
+		c.rateLimiter = &RateLimiter{byHost: make(map[string]*tokenBucket)}
+	}
+	return c.rateLimiter
+}
+
+// bucketFor returns the token bucket that applies to host, or nil if no
+// rate limit has been configured for it.
+func (r *RateLimiter) bucketFor(host string) *tokenBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.byHost[host]; ok {
+		return b
+	}
+	return r.general
+}
+
+// rateLimitMiddleware blocks until the request's target host has a token
+// available before letting the request continue down the chain.
+func (c *Client) rateLimitMiddleware(cli *Client, req *http.Request) (*ClientResponse, error) {
+	if bucket := c.rateLimiter.bucketFor(req.URL.Host); bucket != nil {
+		if err := bucket.wait(req.Context()); err != nil {
+			c.stats.addRateLimited()
+			return nil, err
+		}
+	}
+	return cli.MiddlewareNext(req)
+}
+
+// tokenBucket is a minimal token-bucket limiter refilled at a constant
+// rate up to a maximum burst size.
+type tokenBucket struct {
+	mu         sync.Mutex
+	rps        float64
+	burst      float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rps:        rps,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// wait blocks until a token is available, or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay, ok := b.take()
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take attempts to consume a token, returning the delay to wait before
+// trying again if none is currently available.
+func (b *tokenBucket) take() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.lastRefill).Seconds() * b.rps
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.lastRefill = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / b.rps * float64(time.Second)), false
+}
@@ -0,0 +1,66 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_BurstThenRefill(t *testing.T) {
+	b := newTokenBucket(10, 2)
+
+	if _, ok := b.take(); !ok {
+		t.Fatalf("expected first token to be available from burst")
+	}
+	if _, ok := b.take(); !ok {
+		t.Fatalf("expected second token to be available from burst")
+	}
+	if _, ok := b.take(); ok {
+		t.Fatalf("expected burst to be exhausted")
+	}
+
+	// Force the bucket to look like its last refill was long enough ago
+	// that a full token has accrued, rather than sleeping in the test.
+	b.mu.Lock()
+	b.lastRefill = time.Now().Add(-200 * time.Millisecond)
+	b.mu.Unlock()
+
+	if _, ok := b.take(); !ok {
+		t.Fatalf("expected a token to have been refilled after 200ms at 10rps")
+	}
+}
+
+func TestTokenBucket_DelayWhenEmpty(t *testing.T) {
+	b := newTokenBucket(1, 1)
+	if _, ok := b.take(); !ok {
+		t.Fatalf("expected the burst token to be available")
+	}
+	delay, ok := b.take()
+	if ok {
+		t.Fatalf("expected bucket to be empty")
+	}
+	if delay <= 0 || delay > time.Second {
+		t.Fatalf("expected delay in (0, 1s], got %v", delay)
+	}
+}
+
+func TestRateLimiter_BucketForFallsBackToGeneral(t *testing.T) {
+	r := &RateLimiter{byHost: make(map[string]*tokenBucket)}
+	if b := r.bucketFor("example.com"); b != nil {
+		t.Fatalf("expected no bucket configured yet")
+	}
+	r.general = newTokenBucket(5, 5)
+	if b := r.bucketFor("example.com"); b == nil {
+		t.Fatalf("expected the general bucket to apply")
+	}
+	specific := newTokenBucket(1, 1)
+	r.byHost["other.com"] = specific
+	if b := r.bucketFor("other.com"); b != specific {
+		t.Fatalf("expected the host-specific bucket to override the general one")
+	}
+}
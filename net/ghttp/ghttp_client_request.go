@@ -9,7 +9,6 @@ package ghttp
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"github.com/gogf/gf"
 	"github.com/gogf/gf/internal/intlog"
@@ -18,11 +17,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/label"
 	"go.opentelemetry.io/otel/trace"
-	"io"
 	"io/ioutil"
-	"mime/multipart"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -30,8 +26,6 @@ import (
 	"github.com/gogf/gf/text/gregex"
 	"github.com/gogf/gf/text/gstr"
 	"github.com/gogf/gf/util/gconv"
-
-	"github.com/gogf/gf/os/gfile"
 )
 
 // Get send GET request and returns the response object.
@@ -101,6 +95,24 @@ func (c *Client) DoRequest(method, url string, data ...interface{}) (resp *Clien
 		return nil, err
 	}
 
+	// Cassette replay, short-circuiting before the transport and the
+	// middleware chain entirely.
+	if c.cassette != nil && c.cassette.mode != CassetteRecord {
+		var (
+			cassetteResp *ClientResponse
+			matched      bool
+		)
+		if cassetteResp, matched, err = c.cassette.replay(c, req); err != nil {
+			return nil, err
+		}
+		if matched {
+			return cassetteResp, nil
+		}
+		if c.cassette.mode == CassetteReplay {
+			return nil, fmt.Errorf(`ghttp: no cassette interaction recorded for %s %s`, method, url)
+		}
+	}
+
 	// Tracing.
 	tr := otel.GetTracerProvider().Tracer(
 		"github.com/gogf/gf/net/ghttp.client",
@@ -114,10 +126,18 @@ func (c *Client) DoRequest(method, url string, data ...interface{}) (resp *Clien
 	}
 	req = req.WithContext(ctx)
 
-	// Client middleware.
-	if len(c.middlewareHandler) > 0 {
-		mdlHandlers := make([]ClientHandlerFunc, 0, len(c.middlewareHandler)+1)
-		mdlHandlers = append(mdlHandlers, c.middlewareHandler...)
+	// Client middleware. The rate limiter and circuit breaker, when
+	// configured, run ahead of any user middleware so they can short-circuit
+	// a request before it does any real work.
+	mdlHandlers := make([]ClientHandlerFunc, 0, len(c.middlewareHandler)+3)
+	if c.rateLimiter != nil {
+		mdlHandlers = append(mdlHandlers, c.rateLimitMiddleware)
+	}
+	if c.breaker != nil {
+		mdlHandlers = append(mdlHandlers, c.circuitBreakerMiddleware)
+	}
+	mdlHandlers = append(mdlHandlers, c.middlewareHandler...)
+	if len(mdlHandlers) > 0 {
 		mdlHandlers = append(mdlHandlers, func(cli *Client, r *http.Request) (*ClientResponse, error) {
 			return cli.callRequest(r)
 		})
@@ -132,6 +152,14 @@ func (c *Client) DoRequest(method, url string, data ...interface{}) (resp *Clien
 		resp, err = c.callRequest(req)
 	}
 
+	// Requests/Failures are tracked here unconditionally, rather than inside
+	// circuitBreakerMiddleware, so that Stats() stays meaningful for clients
+	// that use SetRateLimit without ever configuring a CircuitBreaker.
+	c.stats.addRequest()
+	if !requestSucceeded(resp, err) {
+		c.stats.addFailure()
+	}
+
 	// Auto saving cookie content.
 	if c.browserMode && resp != nil {
 		now := time.Now()
@@ -194,52 +222,48 @@ func (c *Client) prepareRequest(method, url string, data ...interface{}) (req *h
 			return nil, err
 		}
 	} else {
-		if strings.Contains(param, "@file:") {
-			// File uploading request.
-			buffer := new(bytes.Buffer)
-			writer := multipart.NewWriter(buffer)
-			for _, item := range strings.Split(param, "&") {
-				array := strings.Split(item, "=")
-				if len(array[1]) > 6 && strings.Compare(array[1][0:6], "@file:") == 0 {
-					path := array[1][6:]
-					if !gfile.Exists(path) {
-						return nil, errors.New(fmt.Sprintf(`"%s" does not exist`, path))
-					}
-					if file, err := writer.CreateFormFile(array[0], gfile.Basename(path)); err == nil {
-						if f, err := os.Open(path); err == nil {
-							if _, err = io.Copy(file, f); err != nil {
-								if err := f.Close(); err != nil {
-									intlog.Errorf(`%+v`, err)
-								}
-								return nil, err
-							}
-							if err := f.Close(); err != nil {
-								intlog.Errorf(`%+v`, err)
-							}
-						} else {
-							return nil, err
-						}
-					} else {
-						return nil, err
-					}
-				} else {
-					if err = writer.WriteField(array[0], array[1]); err != nil {
-						return nil, err
-					}
-				}
+		var (
+			uploadFields []fileUploadField
+			hasFile      bool
+		)
+		if len(data) > 0 {
+			uploadFields, hasFile = clientFileUploadFields(data[0])
+		}
+		if !hasFile && strings.Contains(param, "@file:") {
+			if uploadFields, err = parseFileUploadParam(param); err != nil {
+				return nil, err
+			}
+			hasFile = true
+		}
+		switch {
+		case hasFile && c.bodyStreaming:
+			// Streaming file uploading request: the multipart body is written
+			// into an io.Pipe on the fly by buildStreamingMultipartBody, so the
+			// payload is never fully buffered in memory.
+			body, contentType, ferr := c.buildStreamingMultipartBody(uploadFields)
+			if ferr != nil {
+				return nil, ferr
 			}
-			// Close finishes the multipart message and writes the trailing
-			// boundary end line to the output.
-			if err = writer.Close(); err != nil {
+			if req, err = http.NewRequest(method, url, body); err != nil {
 				return nil, err
 			}
+			req.Header.Set("Content-Type", contentType)
+			// Leaving ContentLength unset (-1) makes net/http send the request
+			// using chunked transfer encoding.
+			req.ContentLength = -1
 
+		case hasFile:
+			// File uploading request.
+			buffer, contentType, ferr := c.buildBufferedMultipartBody(uploadFields)
+			if ferr != nil {
+				return nil, ferr
+			}
 			if req, err = http.NewRequest(method, url, buffer); err != nil {
 				return nil, err
-			} else {
-				req.Header.Set("Content-Type", writer.FormDataContentType())
 			}
-		} else {
+			req.Header.Set("Content-Type", contentType)
+
+		default:
 			// Normal request.
 			paramBytes := []byte(param)
 			if req, err = http.NewRequest(method, url, bytes.NewReader(paramBytes)); err != nil {
@@ -299,6 +323,16 @@ func (c *Client) prepareRequest(method, url string, data ...interface{}) (req *h
 	if c.agent != "" {
 		req.Header.Set("User-Agent", c.agent)
 	}
+	// Accept-Encoding, used together with the transparent response decompression.
+	if c.acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", c.acceptEncoding)
+	}
+	// Request signing, executed last so the authenticator sees the final request.
+	if c.authenticator != nil {
+		if err = c.authenticator.Sign(req); err != nil {
+			return nil, err
+		}
+	}
 	return req, nil
 }
 
@@ -307,30 +341,58 @@ func (c *Client) prepareRequest(method, url string, data ...interface{}) (req *h
 func (c *Client) callRequest(req *http.Request) (resp *ClientResponse, err error) {
 	resp = &ClientResponse{
 		request: req,
+		client:  c,
 	}
-	// The request body can be reused for dumping
-	// raw HTTP request-response procedure.
-	reqBodyContent, _ := ioutil.ReadAll(req.Body)
-	resp.requestBody = reqBodyContent
-	req.Body = utils.NewReadCloser(reqBodyContent, false)
-	for {
-		if resp.Response, err = c.Do(req); err != nil {
-			// The response might not be nil when err != nil.
-			if resp.Response != nil {
-				if err := resp.Response.Body.Close(); err != nil {
-					intlog.Errorf(`%+v`, err)
-				}
-			}
-			if c.retryCount > 0 {
-				c.retryCount--
-				time.Sleep(c.retryInterval)
-			} else {
-				//return resp, err
-				break
+	_, streaming := req.Body.(*streamingBody)
+	var reqBodyContent []byte
+	if !streaming {
+		// The request body can be reused for dumping
+		// raw HTTP request-response procedure.
+		reqBodyContent, _ = ioutil.ReadAll(req.Body)
+		resp.requestBody = reqBodyContent
+		req.Body = utils.NewReadCloser(reqBodyContent, false)
+	}
+	originalHeader := req.Header.Clone()
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+	for attempt := 0; ; attempt++ {
+		resp.Response, err = c.Do(req)
+		if err == nil {
+			if dErr := decompressBody(resp.Response); dErr != nil {
+				intlog.Errorf(`%+v`, dErr)
 			}
-		} else {
+		}
+		retry, delay := policy.ShouldRetry(attempt, req, resp.Response, err)
+		if retry && streaming {
+			// A *streamingBody's io.Pipe has already been drained (and
+			// closed) by this attempt's transport, and there's no captured
+			// content to rebuild it from here, so retrying would either
+			// resend a truncated body or fail on the closed pipe. Retrying
+			// is only safe for bodies callRequest can rewind from
+			// reqBodyContent.
+			retry = false
+		}
+		if !retry {
 			break
 		}
+		// The response might not be nil when err != nil.
+		if resp.Response != nil {
+			if cErr := resp.Response.Body.Close(); cErr != nil {
+				intlog.Errorf(`%+v`, cErr)
+			}
+		}
+		time.Sleep(delay)
+		// Retries may re-send the body, so rewind it from the captured
+		// content and reset headers mutated by a previous attempt.
+		if !streaming {
+			req.Body = utils.NewReadCloser(reqBodyContent, false)
+		}
+		req.Header = originalHeader.Clone()
+	}
+	if err == nil && c.cassette != nil && c.cassette.mode != CassetteReplay {
+		c.cassette.record(req, resp, reqBodyContent)
 	}
 	return resp, err
 }
@@ -0,0 +1,127 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides, after a request attempt, whether callRequest should
+// retry and how long it should wait before doing so. `attempt` is the
+// zero-based index of the attempt that just completed; `resp` and `err` are
+// its outcome, exactly as returned by Client.Do.
+type RetryPolicy interface {
+	ShouldRetry(attempt int, req *http.Request, resp *http.Response, err error) (retry bool, delay time.Duration)
+}
+
+// SetRetryPolicy registers the RetryPolicy used by callRequest. If none is
+// set, a default exponential-backoff-with-full-jitter policy is used.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retryPolicy = p
+}
+
+// defaultRetryPolicy is used by callRequest whenever no RetryPolicy has
+// been registered through SetRetryPolicy.
+var defaultRetryPolicy = NewExponentialBackoffRetryPolicy(100*time.Millisecond, 10*time.Second, 3)
+
+// ExponentialBackoffRetryPolicy retries on network errors and on
+// 429/502/503/504 responses, honoring a "Retry-After" header when present.
+// Non-idempotent methods (POST, PATCH, CONNECT) are only retried when the
+// server explicitly signals it's safe to do so through "Retry-After";
+// otherwise they fail on the first attempt like before.
+type ExponentialBackoffRetryPolicy struct {
+	Base        time.Duration // Base delay before the first retry.
+	Cap         time.Duration // Upper bound on the computed delay.
+	MaxAttempts int           // Total attempts allowed, including the first one.
+}
+
+// NewExponentialBackoffRetryPolicy creates and returns an
+// ExponentialBackoffRetryPolicy with the given base delay, delay cap and
+// maximum number of attempts.
+func NewExponentialBackoffRetryPolicy(base, cap time.Duration, maxAttempts int) *ExponentialBackoffRetryPolicy {
+	return &ExponentialBackoffRetryPolicy{Base: base, Cap: cap, MaxAttempts: maxAttempts}
+}
+
+func (p *ExponentialBackoffRetryPolicy) ShouldRetry(attempt int, req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	if attempt+1 >= p.MaxAttempts {
+		return false, 0
+	}
+	if err != nil {
+		if !isIdempotentMethod(req.Method) {
+			// A transport error carries no server signal that resending
+			// is safe.
+			return false, 0
+		}
+		return true, p.backoff(attempt)
+	}
+	if resp == nil || !isRetryableStatus(resp.StatusCode) {
+		return false, 0
+	}
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if !isIdempotentMethod(req.Method) && !hasRetryAfter {
+		// Retrying a non-idempotent request could duplicate its side
+		// effects, so it's only done when the server explicitly says so.
+		return false, 0
+	}
+	if hasRetryAfter {
+		return true, retryAfter
+	}
+	return true, p.backoff(attempt)
+}
+
+// backoff computes a full-jitter exponential delay: rand(0, min(cap, base*2^attempt)).
+func (p *ExponentialBackoffRetryPolicy) backoff(attempt int) time.Duration {
+	upper := float64(p.Base) * math.Pow(2, float64(attempt))
+	if cap := float64(p.Cap); upper > cap {
+		upper = cap
+	}
+	return time.Duration(rand.Float64() * upper)
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a "Retry-After" header value, which is either a
+// number of seconds or an HTTP-date, and returns the remaining delay.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,123 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoffRetryPolicy_MaxAttempts(t *testing.T) {
+	p := NewExponentialBackoffRetryPolicy(10*time.Millisecond, time.Second, 3)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	if retry, _ := p.ShouldRetry(0, req, nil, errTransport); !retry {
+		t.Fatalf("expected attempt 0 to retry")
+	}
+	if retry, _ := p.ShouldRetry(1, req, nil, errTransport); !retry {
+		t.Fatalf("expected attempt 1 to retry")
+	}
+	if retry, _ := p.ShouldRetry(2, req, nil, errTransport); retry {
+		t.Fatalf("expected attempt 2 (the 3rd attempt) not to retry, MaxAttempts=3")
+	}
+}
+
+func TestExponentialBackoffRetryPolicy_NonIdempotentRequiresRetryAfter(t *testing.T) {
+	p := NewExponentialBackoffRetryPolicy(10*time.Millisecond, time.Second, 5)
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	if retry, _ := p.ShouldRetry(0, req, resp, nil); retry {
+		t.Fatalf("expected POST without Retry-After not to retry")
+	}
+
+	resp.Header.Set("Retry-After", "1")
+	retry, delay := p.ShouldRetry(0, req, resp, nil)
+	if !retry {
+		t.Fatalf("expected POST with Retry-After to retry")
+	}
+	if delay != time.Second {
+		t.Fatalf("expected delay to honor Retry-After, got %v", delay)
+	}
+}
+
+func TestExponentialBackoffRetryPolicy_Backoff(t *testing.T) {
+	p := NewExponentialBackoffRetryPolicy(100*time.Millisecond, time.Second, 10)
+	for attempt := 0; attempt < 5; attempt++ {
+		for i := 0; i < 20; i++ {
+			d := p.backoff(attempt)
+			if d < 0 || d > p.Cap {
+				t.Fatalf("attempt %d: backoff %v out of [0, %v]", attempt, d, p.Cap)
+			}
+		}
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+		http.StatusOK:                  false,
+		http.StatusInternalServerError: false,
+	}
+	for status, want := range cases {
+		if got := isRetryableStatus(status); got != want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	idempotent := []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}
+	for _, m := range idempotent {
+		if !isIdempotentMethod(m) {
+			t.Errorf("expected %s to be idempotent", m)
+		}
+	}
+	nonIdempotent := []string{http.MethodPost, http.MethodPatch, http.MethodConnect}
+	for _, m := range nonIdempotent {
+		if isIdempotentMethod(m) {
+			t.Errorf("expected %s not to be idempotent", m)
+		}
+	}
+}
+
+func TestParseRetryAfter_Seconds(t *testing.T) {
+	d, ok := parseRetryAfter("5")
+	if !ok || d != 5*time.Second {
+		t.Fatalf("parseRetryAfter(5) = %v, %v", d, ok)
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	future := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	d, ok := parseRetryAfter(future)
+	if !ok {
+		t.Fatalf("expected HTTP-date Retry-After to parse")
+	}
+	if d <= 0 || d > 31*time.Second {
+		t.Fatalf("parseRetryAfter(%s) = %v, out of expected range", future, d)
+	}
+}
+
+func TestParseRetryAfter_Invalid(t *testing.T) {
+	if _, ok := parseRetryAfter(""); ok {
+		t.Fatalf("expected empty Retry-After to be invalid")
+	}
+	if _, ok := parseRetryAfter("not-a-date"); ok {
+		t.Fatalf("expected garbage Retry-After to be invalid")
+	}
+}
+
+var errTransport = &testTransportError{}
+
+type testTransportError struct{}
+
+func (e *testTransportError) Error() string { return "transport error" }
@@ -0,0 +1,237 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// websocketGUID is the magic value defined by RFC 6455, appended to the
+// client's "Sec-WebSocket-Key" before hashing to compute the expected
+// "Sec-WebSocket-Accept" response header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebsocketConn is a duplex connection to a server reached through the
+// RFC 6455 handshake performed by Client.Websocket. It wraps the hijacked
+// TCP (or TLS) connection with minimal frame read/write helpers.
+type WebsocketConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// Websocket performs the RFC 6455 handshake against rawURL ("ws://" or
+// "wss://"), reusing the client's cookies, headers, authenticator and TLS
+// config, and returns a WebsocketConn wrapping the upgraded connection.
+//
+// The handshake request goes through prepareRequest, so cookies, custom
+// headers and the registered ClientAuthenticator apply exactly as they
+// would for any other request. The upgraded connection itself is a
+// long-lived duplex stream rather than a single request/response, so it
+// doesn't go through DoRequest's tracing/retry middleware chain.
+func (c *Client) Websocket(rawURL string, header http.Header) (*WebsocketConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return nil, fmt.Errorf(`ghttp: unsupported websocket scheme "%s"`, u.Scheme)
+	}
+
+	req, err := c.prepareRequest(http.MethodGet, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	key, err := newWebsocketKey()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	conn, err := c.dialWebsocket(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		!strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		conn.Close()
+		return nil, fmt.Errorf("ghttp: websocket handshake failed with status %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		conn.Close()
+		return nil, errors.New("ghttp: invalid Sec-WebSocket-Accept value")
+	}
+	return &WebsocketConn{conn: conn, br: br}, nil
+}
+
+// dialWebsocket opens the raw TCP/TLS connection the handshake request is
+// written to, reusing the client's TLS config for "https"/"wss" targets.
+func (c *Client) dialWebsocket(u *url.URL) (net.Conn, error) {
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		if u.Scheme == "https" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+	if u.Scheme != "https" {
+		return net.Dial("tcp", host)
+	}
+	tlsConfig := &tls.Config{ServerName: u.Hostname()}
+	if t, ok := c.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		tlsConfig = t.TLSClientConfig.Clone()
+	}
+	return tls.Dial("tcp", host, tlsConfig)
+}
+
+func newWebsocketKey() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func websocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Websocket frame opcodes, as defined by RFC 6455.
+const (
+	WebsocketOpText   byte = 0x1
+	WebsocketOpBinary byte = 0x2
+	WebsocketOpClose  byte = 0x8
+	WebsocketOpPing   byte = 0x9
+	WebsocketOpPong   byte = 0xA
+)
+
+// WriteMessage writes a single, unfragmented WebSocket frame of the given
+// opcode. Per RFC 6455, frames sent by a client must be masked.
+func (w *WebsocketConn) WriteMessage(opcode byte, data []byte) error {
+	var header bytes.Buffer
+	header.WriteByte(0x80 | opcode) // FIN + opcode, no fragmentation.
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return err
+	}
+	length := len(data)
+	switch {
+	case length <= 125:
+		header.WriteByte(0x80 | byte(length))
+	case length <= 0xFFFF:
+		header.WriteByte(0x80 | 126)
+		_ = binary.Write(&header, binary.BigEndian, uint16(length))
+	default:
+		header.WriteByte(0x80 | 127)
+		_ = binary.Write(&header, binary.BigEndian, uint64(length))
+	}
+	header.Write(mask)
+	masked := make([]byte, length)
+	for i, b := range data {
+		masked[i] = b ^ mask[i%4]
+	}
+	if _, err := w.conn.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.conn.Write(masked)
+	return err
+}
+
+// WriteText writes a text frame.
+func (w *WebsocketConn) WriteText(s string) error {
+	return w.WriteMessage(WebsocketOpText, []byte(s))
+}
+
+// ReadMessage reads a single WebSocket frame and returns its opcode and
+// payload. It doesn't reassemble fragmented messages.
+func (w *WebsocketConn) ReadMessage() (opcode byte, payload []byte, err error) {
+	first, err := w.br.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	opcode = first & 0x0F
+	second, err := w.br.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	masked := second&0x80 != 0
+	length := int64(second & 0x7F)
+	switch length {
+	case 126:
+		var ext uint16
+		if err = binary.Read(w.br, binary.BigEndian, &ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(ext)
+	case 127:
+		var ext uint64
+		if err = binary.Read(w.br, binary.BigEndian, &ext); err != nil {
+			return 0, nil, err
+		}
+		length = int64(ext)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(w.br, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(w.br, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// Close closes the underlying connection.
+func (w *WebsocketConn) Close() error {
+	return w.conn.Close()
+}
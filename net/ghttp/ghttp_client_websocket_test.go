@@ -0,0 +1,107 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package ghttp
+
+import (
+	"bufio"
+	"net"
+	"testing"
+)
+
+func TestWebsocketConn_WriteReadRoundTrip(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	clientConn := &WebsocketConn{conn: client, br: bufio.NewReader(client)}
+	serverConn := &WebsocketConn{conn: server, br: bufio.NewReader(server)}
+
+	done := make(chan struct{})
+	var gotOpcode byte
+	var gotPayload []byte
+	var readErr error
+	go func() {
+		gotOpcode, gotPayload, readErr = serverConn.ReadMessage()
+		close(done)
+	}()
+
+	if err := clientConn.WriteText("hello websocket"); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	<-done
+
+	if readErr != nil {
+		t.Fatalf("ReadMessage failed: %v", readErr)
+	}
+	if gotOpcode != WebsocketOpText {
+		t.Fatalf("opcode = %#x, want %#x", gotOpcode, WebsocketOpText)
+	}
+	if string(gotPayload) != "hello websocket" {
+		t.Fatalf("payload = %q, want %q", gotPayload, "hello websocket")
+	}
+}
+
+func TestWebsocketConn_ExtendedLengthEncodings(t *testing.T) {
+	cases := []struct {
+		name string
+		size int
+	}{
+		// 125 < n <= 0xFFFF: the 2-byte (126) extended-length branch.
+		{"16-bit length", 5000},
+		// n > 0xFFFF: the 8-byte (127) extended-length branch.
+		{"64-bit length", 70000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			defer server.Close()
+
+			clientConn := &WebsocketConn{conn: client, br: bufio.NewReader(client)}
+			serverConn := &WebsocketConn{conn: server, br: bufio.NewReader(server)}
+
+			payload := make([]byte, c.size)
+			for i := range payload {
+				payload[i] = byte(i)
+			}
+
+			done := make(chan struct{})
+			var gotPayload []byte
+			var readErr error
+			go func() {
+				_, gotPayload, readErr = serverConn.ReadMessage()
+				close(done)
+			}()
+
+			if err := clientConn.WriteMessage(WebsocketOpBinary, payload); err != nil {
+				t.Fatalf("WriteMessage failed: %v", err)
+			}
+			<-done
+
+			if readErr != nil {
+				t.Fatalf("ReadMessage failed: %v", readErr)
+			}
+			if len(gotPayload) != len(payload) {
+				t.Fatalf("payload length = %d, want %d", len(gotPayload), len(payload))
+			}
+			for i := range payload {
+				if gotPayload[i] != payload[i] {
+					t.Fatalf("payload mismatch at byte %d: got %d, want %d", i, gotPayload[i], payload[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWebsocketAccept(t *testing.T) {
+	// Worked example straight from RFC 6455 section 1.3.
+	const key = "dGhlIHNhbXBsZSBub25jZQ=="
+	const want = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got := websocketAccept(key); got != want {
+		t.Fatalf("websocketAccept(%q) = %q, want %q", key, got, want)
+	}
+}